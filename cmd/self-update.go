@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
 	"strings"
@@ -33,7 +39,51 @@ type selfUpdateCmd struct {
 }
 
 type selfUpdateOpt struct {
-	tag bool
+	tag        bool
+	version    string
+	filters    []string
+	publicKey  string
+	rollback   bool
+	noRollback bool
+	channel    string
+}
+
+// ghAsset is a single asset attached to a GitHub release, trimmed down to
+// the fields self-update cares about.
+type ghAsset struct {
+	Name string
+	URL  string
+}
+
+// ghRelease is a single GitHub release, trimmed to the fields self-update
+// needs to pick a release out by channel.
+type ghRelease struct {
+	Tag        string
+	Prerelease bool
+	Draft      bool
+	Assets     []ghAsset
+}
+
+// Conf is afx's parsed config. self-update reads its SelfUpdate section
+// from it; while Conf is nil, no config filters or public key apply.
+var Conf *config.Config
+
+// configFilters returns the asset filters set in Conf.SelfUpdate.Filters,
+// or nil if no config was loaded.
+func configFilters() []string {
+	if Conf == nil {
+		return nil
+	}
+	return Conf.SelfUpdate.Filters
+}
+
+// configPublicKey returns the hex-encoded ed25519 public key set in
+// Conf.SelfUpdate.PublicKey, or "" if no config was loaded.
+func configPublicKey() string {
+	if Conf == nil {
+		return ""
+	}
+	return Conf.SelfUpdate.PublicKey
 }
 
 var (
@@ -69,8 +119,16 @@ func newSelfUpdateCmd() *cobra.Command {
 				return err
 			}
 
+			if c.opt.rollback {
+				return c.doRollback()
+			}
+
+			if c.opt.version != "" {
+				return c.selectTag(args, c.opt.version)
+			}
+
 			if c.opt.tag {
-				return c.selectTag(args)
+				return c.selectTag(args, "")
 			}
 
 			return c.run(args)
@@ -80,40 +138,464 @@ func newSelfUpdateCmd() *cobra.Command {
 	flag := selfUpdateCmd.Flags()
 	flag.BoolVarP(&c.opt.tag, "select", "", false, "help message")
 	flag.MarkHidden("select")
+	flag.StringArrayVarP(&c.opt.filters, "filter", "", nil, "only consider assets matching this regexp (can be repeated), composes with SelfUpdate.Filters in config")
+	flag.StringVarP(&c.opt.version, "version", "", "", "update (or downgrade) to a specific release tag, e.g. v0.1.10")
+	flag.StringVarP(&c.opt.publicKey, "public-key", "", "", "hex-encoded ed25519 public key to verify signatures with, overrides SelfUpdate.PublicKey")
+	flag.BoolVarP(&c.opt.rollback, "rollback", "", false, "restore the binary update.Apply backed up before the last self-update")
+	flag.BoolVarP(&c.opt.noRollback, "no-rollback", "", false, "don't automatically roll back if the updated binary fails its post-update check")
+	flag.StringVarP(&c.opt.channel, "channel", "", "stable", "release channel to update from: stable or prerelease")
 
 	return selfUpdateCmd
 }
 
-func (c *selfUpdateCmd) selectTag(args []string) error {
+// listReleases fetches every release of Repository from the GitHub API,
+// including prereleases and drafts, so callers can filter by channel
+// themselves instead of relying on selfupdate.DetectLatest, which always
+// skips them.
+func listReleases() ([]ghRelease, error) {
 	resp, err := http.Get(fmt.Sprintf("https://api.github.com/repos/%s/releases", Repository))
 	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []ghRelease
+	gjson.Parse(string(body)).ForEach(func(_, rel gjson.Result) bool {
+		r := ghRelease{
+			Tag:        rel.Get("tag_name").String(),
+			Prerelease: rel.Get("prerelease").Bool(),
+			Draft:      rel.Get("draft").Bool(),
+		}
+		rel.Get("assets").ForEach(func(_, a gjson.Result) bool {
+			r.Assets = append(r.Assets, ghAsset{
+				Name: a.Get("name").String(),
+				URL:  a.Get("browser_download_url").String(),
+			})
+			return true
+		})
+		releases = append(releases, r)
+		return true
+	})
+
+	return releases, nil
+}
+
+// latestForChannel returns the newest non-draft release for channel:
+// "stable" excludes prereleases, "prerelease" includes them.
+func latestForChannel(channel string) (ghRelease, error) {
+	releases, err := listReleases()
+	if err != nil {
+		return ghRelease{}, err
+	}
+
+	return pickLatestForChannel(channel, releases)
+}
+
+// pickLatestForChannel picks the newest non-draft release out of releases
+// for channel: "stable" excludes prereleases, "prerelease" includes them.
+// Split out of latestForChannel so the selection logic can be tested
+// without hitting the GitHub API.
+func pickLatestForChannel(channel string, releases []ghRelease) (ghRelease, error) {
+	var (
+		best    ghRelease
+		bestVer *semver.Version
+	)
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		if channel == "stable" && r.Prerelease {
+			continue
+		}
+		v, err := semver.NewVersion(strings.TrimPrefix(r.Tag, "v"))
+		if err != nil {
+			continue
+		}
+		if bestVer == nil || v.GreaterThan(bestVer) {
+			best, bestVer = r, v
+		}
+	}
+
+	if bestVer == nil {
+		return ghRelease{}, fmt.Errorf("no %s release found for %s", channel, Repository)
+	}
+
+	return best, nil
+}
+
+// resolveLatest finds the newest release on --channel and the asset to use
+// from it, honoring --filter. An empty assetURL means the running binary
+// is already on the latest version for that channel. assets is every asset
+// on the chosen release, for the checksum/signature lookups in
+// verifyDownload.
+func (c *selfUpdateCmd) resolveLatest() (version, assetURL, assetName string, assets []ghAsset, err error) {
+	channel := c.opt.channel
+	if channel == "" {
+		channel = "stable"
+	}
+	if channel != "stable" && channel != "prerelease" {
+		return "", "", "", nil, fmt.Errorf("unknown --channel %q, must be one of: stable, prerelease", channel)
+	}
+
+	if channel == "stable" && len(c.filters()) == 0 {
+		latest, found, derr := selfupdate.DetectLatest(Repository)
+		if derr != nil {
+			return "", "", "", nil, errors.Wrap(derr, "error occurred while detecting version")
+		}
+		if !found {
+			return "", "", "", nil, fmt.Errorf("latest version for %s/%s could not be found from GitHub repository",
+				runtime.GOOS, runtime.GOARCH)
+		}
+
+		releaseAssets, aerr := latestReleaseAssets()
+		if aerr != nil {
+			return "", "", "", nil, errors.Wrap(aerr, "error occurred while listing release assets")
+		}
+
+		if latest.LessOrEqual(Version) {
+			return latest.Version(), "", "", releaseAssets, nil
+		}
+		return latest.Version(), latest.AssetURL, latest.AssetName, releaseAssets, nil
+	}
+
+	rel, rerr := latestForChannel(channel)
+	if rerr != nil {
+		return "", "", "", nil, errors.Wrap(rerr, "error occurred while detecting version")
+	}
+
+	version = strings.TrimPrefix(rel.Tag, "v")
+	if v, verr := semver.NewVersion(version); verr == nil {
+		if cur, cerr := semver.NewVersion(Version); cerr == nil && !v.GreaterThan(cur) {
+			return version, "", "", rel.Assets, nil
+		}
+	}
+
+	asset, aerr := c.selectAsset(rel.Assets)
+	if aerr != nil {
+		return "", "", "", nil, aerr
+	}
+
+	return version, asset.URL, asset.Name, rel.Assets, nil
+}
+
+// doRollback restores the previous binary from the .old sidecar update.Apply
+// leaves next to the current executable. Use this after a self-update that
+// turned out to be broken in a way the automatic post-update check missed.
+func (c *selfUpdateCmd) doRollback() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return errors.New("could not locate executable path")
+	}
+
+	old := exe + ".old"
+	log.Printf("[DEBUG] (self-update) looking for previous binary at %s", old)
+	if _, err := os.Stat(old); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("no previous binary found at %s", old))
+	}
+
+	fp, err := os.Open(old)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while opening previous binary")
+	}
+	defer fp.Close()
+
+	if err := update.Apply(fp, update.Options{TargetPath: exe, OldSavePath: exe + ".old"}); err != nil {
+		return errors.Wrap(err, "error occurred while rolling back")
+	}
+
+	color.New(color.Bold).Printf("Rolled back to the binary saved at %s\n", old)
+	return nil
+}
+
+// verifyApplied runs the freshly applied binary with --version to catch an
+// update that was written but doesn't actually work (truncated download,
+// wrong asset, etc). want, when non-empty, is also checked for in the
+// output.
+func verifyApplied(exe, want string) error {
+	out, err := exec.Command(exe, "--version").CombinedOutput()
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("new binary failed to run: %s", strings.TrimSpace(string(out))))
+	}
+	if want != "" && !containsVersionField(string(out), want) {
+		return fmt.Errorf("new binary reports an unexpected version: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// containsVersionField reports whether want appears as one of the
+// whitespace-delimited fields of out, rather than merely as a substring.
+// A raw substring check would let e.g. want="0.1.1" false-positive against
+// out containing "0.1.10", which would silently defeat verifyApplied's
+// rollback safety net.
+func containsVersionField(out, want string) bool {
+	for _, field := range strings.Fields(out) {
+		if field == want {
+			return true
+		}
+	}
+	return false
+}
+
+// rollbackOrReport rolls the sidecar .old binary back into place after a
+// failed post-update verification, unless --no-rollback was given, and
+// returns an error describing what happened either way.
+func (c *selfUpdateCmd) rollbackOrReport(verifyErr error) error {
+	if c.opt.noRollback {
+		return errors.Wrap(verifyErr, "update verification failed (rollback disabled via --no-rollback)")
+	}
+
+	log.Printf("[DEBUG] (self-update) update verification failed (%v); rolling back", verifyErr)
+	if err := c.doRollback(); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("update verification failed (%v) and automatic rollback also failed", verifyErr))
+	}
+
+	return errors.Wrap(verifyErr, "update verification failed; automatically rolled back to the previous version")
+}
+
+// verifyDownload checks a downloaded release asset against a companion
+// checksums file and/or detached signature before it's handed to
+// update.Apply. Each check is skipped when its input is absent (no
+// checksums file in the release, no public key configured), so an asset
+// from a release with neither is applied unverified.
+func (c *selfUpdateCmd) verifyDownload(data []byte, name string, assets []ghAsset) error {
+	if err := c.verifyChecksum(data, name, assets); err != nil {
 		return err
 	}
+	return c.verifySignature(data, name, assets)
+}
+
+// verifyChecksum looks for a checksums.txt or SHA256SUMS asset in the same
+// release and, if found, requires name to be listed in it with a matching
+// sha256 digest. Releases without a checksums file are allowed through
+// unverified, since not every release publishes one.
+func (c *selfUpdateCmd) verifyChecksum(data []byte, name string, assets []ghAsset) error {
+	sums := findAsset(assets, "checksums.txt", "SHA256SUMS")
+	if sums == nil {
+		log.Printf("[DEBUG] (self-update) no checksums file in release assets; skipping checksum verification")
+		return nil
+	}
+
+	resp, err := http.Get(sums.URL)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while downloading checksums file")
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while reading checksums file")
+	}
+
+	var want string
+	for _, line := range strings.Split(string(body), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == name {
+			want = fields[0]
+			break
+		}
+	}
+	if want == "" {
+		return fmt.Errorf("%s is not listed in %s", name, sums.Name)
+	}
+
+	got := hex.EncodeToString(func() []byte { sum := sha256.Sum256(data); return sum[:] }())
+	if !strings.EqualFold(got, want) {
+		return fmt.Errorf("checksum mismatch for %s: want %s, got %s", name, want, got)
+	}
+
+	return nil
+}
+
+// verifySignature looks for a <name>.sig or <name>.minisig asset and, if a
+// public key is configured, requires it to verify as an ed25519 signature
+// over data. Without a configured key, signature verification is skipped.
+func (c *selfUpdateCmd) verifySignature(data []byte, name string, assets []ghAsset) error {
+	key := c.opt.publicKey
+	if key == "" {
+		key = configPublicKey()
+	}
+	if key == "" {
+		log.Printf("[DEBUG] (self-update) no public key configured; skipping signature verification")
+		return nil
+	}
+
+	pub, err := hex.DecodeString(key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return errors.New("SelfUpdate.PublicKey is not a valid hex-encoded ed25519 public key")
+	}
+
+	sig := findAsset(assets, name+".sig", name+".minisig")
+	if sig == nil {
+		return fmt.Errorf("no signature asset found for %s, but a public key is configured", name)
+	}
+
+	resp, err := http.Get(sig.URL)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while downloading signature")
+	}
+	defer resp.Body.Close()
+	sigBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while reading signature")
+	}
+
+	if !ed25519.Verify(pub, data, sigBytes) {
+		return fmt.Errorf("signature verification failed for %s", name)
+	}
+
+	return nil
+}
+
+// findAsset returns the first asset whose name case-insensitively matches
+// one of names, or nil if none do.
+func findAsset(assets []ghAsset, names ...string) *ghAsset {
+	for i, a := range assets {
+		for _, name := range names {
+			if strings.EqualFold(a.Name, name) {
+				return &assets[i]
+			}
+		}
+	}
+	return nil
+}
+
+// latestReleaseAssets fetches the assets attached to the latest release of
+// Repository straight from the GitHub API, so callers can see every
+// candidate instead of whatever a single asset a library resolver picked.
+func latestReleaseAssets() ([]ghAsset, error) {
+	resp, err := http.Get(fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", Repository))
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 	body, _ := ioutil.ReadAll(resp.Body)
 
-	var tags []string
-	gjson.Get(string(body), "#.tag_name").
+	var assets []ghAsset
+	gjson.Get(string(body), "assets").
 		ForEach(func(key, value gjson.Result) bool {
-			tags = append(tags, value.String())
+			assets = append(assets, ghAsset{
+				Name: value.Get("name").String(),
+				URL:  value.Get("browser_download_url").String(),
+			})
 			return true
 		})
 
-	var tag string
-	prompt := &survey.Select{
-		Message: "Choose a tag you upgrade/downgrade:",
-		Options: tags,
+	return assets, nil
+}
+
+// matchesPlatform reports whether name looks like it was built for the
+// current OS/arch: it must contain both runtime.GOOS and runtime.GOARCH
+// anywhere in the name, case-insensitively.
+func matchesPlatform(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.Contains(lower, runtime.GOOS) && strings.Contains(lower, runtime.GOARCH)
+}
+
+// filters returns every regex pattern an asset must match: the repeatable
+// --filter flag plus whatever SelfUpdate.Filters is set in afx's config.
+func (c *selfUpdateCmd) filters() []string {
+	return append(append([]string{}, configFilters()...), c.opt.filters...)
+}
+
+// selectAsset narrows assets down to the single one self-update should
+// download: first by OS/arch (see matchesPlatform), then by every filter pattern the
+// user configured (all of them must match). It errors out rather than
+// guessing when zero or more than one candidate remains, so users can
+// iterate on their patterns instead of silently getting the wrong binary.
+func (c *selfUpdateCmd) selectAsset(assets []ghAsset) (ghAsset, error) {
+	var candidates []ghAsset
+	for _, a := range assets {
+		if matchesPlatform(a.Name) {
+			candidates = append(candidates, a)
+		}
+	}
+	log.Printf("[DEBUG] (self-update) %d/%d assets matched %s/%s",
+		len(candidates), len(assets), runtime.GOOS, runtime.GOARCH)
+
+	if patterns := c.filters(); len(patterns) > 0 {
+		var filters []*regexp.Regexp
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return ghAsset{}, errors.Wrap(err, fmt.Sprintf("invalid --filter pattern %q", pattern))
+			}
+			filters = append(filters, re)
+		}
+
+		var filtered []ghAsset
+		for _, cand := range candidates {
+			ok := true
+			for _, re := range filters {
+				if !re.MatchString(cand.Name) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				filtered = append(filtered, cand)
+			} else {
+				log.Printf("[DEBUG] (self-update) rejected %s: did not match all --filter patterns", cand.Name)
+			}
+		}
+		candidates = filtered
+	}
+
+	switch len(candidates) {
+	case 0:
+		return ghAsset{}, errors.New("no asset matched your OS/arch and --filter patterns")
+	case 1:
+		return candidates[0], nil
+	default:
+		var names []string
+		for _, cand := range candidates {
+			names = append(names, cand.Name)
+		}
+		return ghAsset{}, fmt.Errorf(
+			"multiple assets matched (%s); narrow the selection down with --filter",
+			strings.Join(names, ", "))
+	}
+}
+
+// selectTag downloads and applies a specific release tag, bypassing the
+// "already on latest" check in run. When tag is empty it falls back to the
+// interactive picker behind --select; when tag is set (--version) it goes
+// straight to that release, which also allows downgrading.
+func (c *selfUpdateCmd) selectTag(args []string, tag string) error {
+	resp, err := http.Get(fmt.Sprintf("https://api.github.com/repos/%s/releases", Repository))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	if tag == "" {
+		var tags []string
+		gjson.Get(string(body), "#.tag_name").
+			ForEach(func(key, value gjson.Result) bool {
+				tags = append(tags, value.String())
+				return true
+			})
+
+		prompt := &survey.Select{
+			Message: "Choose a tag you upgrade/downgrade:",
+			Options: tags,
+		}
+		survey.AskOne(prompt, &tag, survey.WithValidator(survey.Required))
 	}
-	survey.AskOne(prompt, &tag, survey.WithValidator(survey.Required))
 
 	release := config.GitHubRelease{
-		Name:     "afx",
-		Client:   http.DefaultClient,
-		Assets:   config.Assets{},
-		Filename: "",
+		Name:   "afx",
+		Client: http.DefaultClient,
+		Assets: config.Assets{},
 	}
 
 	rel := gjson.Get(string(body), fmt.Sprintf("#(tag_name==\"%s\")", tag))
+	if !rel.Exists() {
+		return fmt.Errorf("release %q was not found in %s", tag, Repository)
+	}
 	assets := rel.Get("assets")
 	assets.ForEach(func(key, value gjson.Result) bool {
 		name := value.Get("name").String()
@@ -132,6 +614,18 @@ func (c *selfUpdateCmd) selectTag(args []string) error {
 		return err
 	}
 
+	raw, err := ioutil.ReadFile(asset.Path)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while reading downloaded asset")
+	}
+	var ghAssets []ghAsset
+	for _, a := range release.Assets {
+		ghAssets = append(ghAssets, ghAsset{Name: a.Name, URL: a.URL})
+	}
+	if err := c.verifyDownload(raw, asset.Name, ghAssets); err != nil {
+		return errors.Wrap(err, "asset verification failed")
+	}
+
 	if err := release.Unarchive(asset); err != nil {
 		return err
 	}
@@ -147,9 +641,63 @@ func (c *selfUpdateCmd) selectTag(args []string) error {
 		return errors.New("could not locate executable path")
 	}
 
-	return update.Apply(fp, update.Options{
-		TargetPath: exe,
-	})
+	c.printVersionChange(tag)
+
+	if err := update.Apply(fp, update.Options{TargetPath: exe, OldSavePath: exe + ".old"}); err != nil {
+		return errors.Wrap(err, "error occurred while applying update")
+	}
+
+	if err := verifyApplied(exe, strings.TrimPrefix(tag, "v")); err != nil {
+		return c.rollbackOrReport(err)
+	}
+
+	return nil
+}
+
+// printVersionChange prints the same per-version annotations run shows on
+// upgrade, except that when tag is older than the running binary (a
+// --version downgrade) the order is reversed: users need to know about the
+// newest behaviour changes they're stepping away from first.
+func (c *selfUpdateCmd) printVersionChange(tag string) {
+	if Version == "unset" {
+		return
+	}
+
+	start, err := semver.NewVersion(Version)
+	if err != nil {
+		return
+	}
+	stop, err := semver.NewVersion(strings.TrimPrefix(tag, "v"))
+	if err != nil {
+		return
+	}
+
+	var vs []*semver.Version
+	for v := range c.annotation {
+		vs = append(vs, semver.MustParse(v))
+	}
+	sort.Sort(semver.Collection(vs))
+
+	downgrade := stop.LessThan(start)
+	var messages []string
+	for _, v := range vs {
+		switch {
+		case downgrade && v.GreaterThan(stop) && !v.GreaterThan(start):
+			messages = append(messages, fmt.Sprintf("- you are moving BELOW %s: %s", v, c.annotation[v.String()]))
+		case !downgrade && v.GreaterThan(start) && !v.GreaterThan(stop):
+			messages = append(messages, "- "+c.annotation[v.String()])
+		}
+	}
+
+	if downgrade {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	if len(messages) > 0 {
+		fmt.Printf("\nMoving from %s to %q:\n%s\n", Version, tag, strings.Join(messages, "\n"))
+	}
 }
 
 func (c *selfUpdateCmd) run(args []string) error {
@@ -164,25 +712,20 @@ func (c *selfUpdateCmd) run(args []string) error {
 		return errors.New("failed to run self-update")
 	}
 
-	latest, found, err := selfupdate.DetectLatest(Repository)
+	latestVersion, assetURL, assetName, releaseAssets, err := c.resolveLatest()
 	if err != nil {
-		return errors.Wrap(err, "error occurred while detecting version")
-	}
-
-	if !found {
-		return fmt.Errorf("latest version for %s/%s could not be found from GitHub repository",
-			runtime.GOOS, runtime.GOARCH)
+		return err
 	}
 
-	if latest.LessOrEqual(Version) {
-		fmt.Printf("Current version (%s) is the latest\n", Version)
+	if assetURL == "" {
+		fmt.Printf("Current version (%s) is the latest on the %s channel\n", Version, c.opt.channel)
 		return nil
 	}
 
 	yes := false
 	if err := survey.AskOne(&survey.Confirm{
 		Message: fmt.Sprintf("Do you want to update to %s? (current version: %s)",
-			latest.Version(), Version),
+			latestVersion, Version),
 	}, &yes); err != nil {
 		return errors.Wrap(err, "cannot get answer from console")
 	}
@@ -196,11 +739,29 @@ func (c *selfUpdateCmd) run(args []string) error {
 		return errors.New("could not locate executable path")
 	}
 
-	if err := selfupdate.UpdateTo(latest.AssetURL, latest.AssetName, exe); err != nil {
+	resp, err := http.Get(assetURL)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while downloading new version")
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "error occurred while reading new version")
+	}
+
+	if err := c.verifyDownload(data, assetName, releaseAssets); err != nil {
+		return errors.Wrap(err, "asset verification failed")
+	}
+
+	if err := update.Apply(bytes.NewReader(data), update.Options{TargetPath: exe, OldSavePath: exe + ".old"}); err != nil {
 		return errors.Wrap(err, "error occurred while updating binary")
 	}
 
-	color.New(color.Bold).Printf("Successfully updated to version %s\n", latest.Version())
+	if err := verifyApplied(exe, latestVersion); err != nil {
+		return c.rollbackOrReport(err)
+	}
+
+	color.New(color.Bold).Printf("Successfully updated to version %s\n", latestVersion)
 
 	var vs []*semver.Version
 	for v := range c.annotation {
@@ -211,7 +772,7 @@ func (c *selfUpdateCmd) run(args []string) error {
 	var messages []string
 	for _, v := range vs {
 		start := semver.MustParse(Version)
-		stop := semver.MustParse(latest.Version())
+		stop := semver.MustParse(latestVersion)
 
 		log.Printf("[DEBUG] (self-update) Current version: %s", start)
 		log.Printf("[DEBUG] (self-update) Next version:    %s", v)
@@ -227,7 +788,7 @@ func (c *selfUpdateCmd) run(args []string) error {
 
 	if len(messages) > 0 {
 		fmt.Printf("\nTo use %q version:\n%s\n",
-			latest.Version(),
+			latestVersion,
 			strings.Join(messages, "\n"))
 	}
 
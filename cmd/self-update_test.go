@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/b4b4r07/afx/pkg/config"
+)
+
+func TestMatchesPlatform(t *testing.T) {
+	suffix := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{fmt.Sprintf("afx_%s.tar.gz", suffix), true},
+		{fmt.Sprintf("afx_%s", runtime.GOOS), false},
+		{"afx_other_other", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchesPlatform(tt.name); got != tt.want {
+			t.Errorf("matchesPlatform(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectAsset(t *testing.T) {
+	suffix := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+	assets := []ghAsset{
+		{Name: fmt.Sprintf("afx_%s", suffix)},
+		{Name: fmt.Sprintf("afx-musl_%s", suffix)},
+		{Name: "afx_other_other"},
+	}
+
+	t.Run("no filter matches both platform assets", func(t *testing.T) {
+		c := &selfUpdateCmd{}
+		if _, err := c.selectAsset(assets); err == nil {
+			t.Fatal("expected an error for multiple candidates, got nil")
+		}
+	})
+
+	t.Run("filter narrows down to one", func(t *testing.T) {
+		c := &selfUpdateCmd{opt: selfUpdateOpt{filters: []string{"^afx_"}}}
+		got, err := c.selectAsset(assets)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := fmt.Sprintf("afx_%s", suffix)
+		if got.Name != want {
+			t.Errorf("selectAsset() = %q, want %q", got.Name, want)
+		}
+	})
+
+	t.Run("filter matching nothing errors", func(t *testing.T) {
+		c := &selfUpdateCmd{opt: selfUpdateOpt{filters: []string{"nope"}}}
+		if _, err := c.selectAsset(assets); err == nil {
+			t.Fatal("expected an error for zero candidates, got nil")
+		}
+	})
+
+	t.Run("config filters narrow down to one, composing with --filter", func(t *testing.T) {
+		defer func() { Conf = nil }()
+		Conf = &config.Config{SelfUpdate: config.SelfUpdate{Filters: []string{"^afx_"}}}
+
+		c := &selfUpdateCmd{opt: selfUpdateOpt{filters: []string{suffix}}}
+		got, err := c.selectAsset(assets)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := fmt.Sprintf("afx_%s", suffix)
+		if got.Name != want {
+			t.Errorf("selectAsset() = %q, want %q", got.Name, want)
+		}
+	})
+}
+
+func TestContainsVersionField(t *testing.T) {
+	tests := []struct {
+		out  string
+		want string
+		ok   bool
+	}{
+		{"afx version 0.1.1\n", "0.1.1", true},
+		{"afx version 0.1.10\n", "0.1.1", false},
+		{"afx version 2.0.0\n", "2.0", false},
+		{"afx version 2.0\n", "2.0", true},
+		{"0.1.1", "0.1.1", true},
+	}
+
+	for _, tt := range tests {
+		if got := containsVersionField(tt.out, tt.want); got != tt.ok {
+			t.Errorf("containsVersionField(%q, %q) = %v, want %v", tt.out, tt.want, got, tt.ok)
+		}
+	}
+}
+
+func TestPickLatestForChannel(t *testing.T) {
+	releases := []ghRelease{
+		{Tag: "v0.2.0", Prerelease: true},
+		{Tag: "v0.1.0"},
+		{Tag: "v0.3.0-rc1", Prerelease: true},
+		{Tag: "v0.0.9", Draft: true},
+	}
+
+	stable, err := pickLatestForChannel("stable", releases)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stable.Tag != "v0.1.0" {
+		t.Errorf("stable channel picked %q, want v0.1.0", stable.Tag)
+	}
+
+	pre, err := pickLatestForChannel("prerelease", releases)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pre.Tag != "v0.3.0-rc1" {
+		t.Errorf("prerelease channel picked %q, want v0.3.0-rc1", pre.Tag)
+	}
+
+	if _, err := pickLatestForChannel("stable", []ghRelease{{Tag: "v1.0.0", Draft: true}}); err == nil {
+		t.Fatal("expected an error when every release is filtered out")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("the new afx binary")
+	sum := sha256.Sum256(data)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s  afx\n%s  other\n", hex.EncodeToString(sum[:]), "deadbeef")
+	}))
+	defer srv.Close()
+
+	c := &selfUpdateCmd{}
+	assets := []ghAsset{{Name: "checksums.txt", URL: srv.URL}}
+
+	if err := c.verifyChecksum(data, "afx", assets); err != nil {
+		t.Errorf("verifyChecksum() with matching sum = %v, want nil", err)
+	}
+
+	if err := c.verifyChecksum([]byte("tampered"), "afx", assets); err == nil {
+		t.Error("verifyChecksum() with mismatched data = nil, want an error")
+	}
+
+	if err := c.verifyChecksum(data, "missing", assets); err == nil {
+		t.Error("verifyChecksum() for an asset not listed in the sums file = nil, want an error")
+	}
+
+	if err := c.verifyChecksum(data, "afx", nil); err != nil {
+		t.Errorf("verifyChecksum() with no checksums file should be skipped, got %v", err)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() failed: %v", err)
+	}
+	data := []byte("the new afx binary")
+	sig := ed25519.Sign(priv, data)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(sig)
+	}))
+	defer srv.Close()
+
+	assets := []ghAsset{{Name: "afx.sig", URL: srv.URL}}
+
+	c := &selfUpdateCmd{opt: selfUpdateOpt{publicKey: hex.EncodeToString(pub)}}
+	if err := c.verifySignature(data, "afx", assets); err != nil {
+		t.Errorf("verifySignature() with a valid signature = %v, want nil", err)
+	}
+
+	if err := c.verifySignature([]byte("tampered"), "afx", assets); err == nil {
+		t.Error("verifySignature() with tampered data = nil, want an error")
+	}
+
+	if err := c.verifySignature(data, "afx", nil); err == nil {
+		t.Error("verifySignature() with no signature asset but a public key configured = nil, want an error")
+	}
+
+	noKey := &selfUpdateCmd{}
+	if err := noKey.verifySignature(data, "afx", nil); err != nil {
+		t.Errorf("verifySignature() with no public key configured should be skipped, got %v", err)
+	}
+
+	t.Run("public key from config", func(t *testing.T) {
+		defer func() { Conf = nil }()
+		Conf = &config.Config{SelfUpdate: config.SelfUpdate{PublicKey: hex.EncodeToString(pub)}}
+
+		fromConfig := &selfUpdateCmd{}
+		if err := fromConfig.verifySignature(data, "afx", assets); err != nil {
+			t.Errorf("verifySignature() with Conf.SelfUpdate.PublicKey set = %v, want nil", err)
+		}
+
+		if err := fromConfig.verifySignature([]byte("tampered"), "afx", assets); err == nil {
+			t.Error("verifySignature() with Conf.SelfUpdate.PublicKey set and tampered data = nil, want an error")
+		}
+	})
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	fn()
+
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestPrintVersionChange(t *testing.T) {
+	c := &selfUpdateCmd{annotation: map[string]string{
+		"0.1.11": "run migrations",
+	}}
+
+	t.Run("upgrade", func(t *testing.T) {
+		Version = "0.1.10"
+		out := captureStdout(t, func() { c.printVersionChange("v0.1.12") })
+		if !bytes.Contains([]byte(out), []byte("run migrations")) {
+			t.Errorf("printVersionChange() upgrade output = %q, want it to mention the 0.1.11 annotation", out)
+		}
+	})
+
+	t.Run("downgrade", func(t *testing.T) {
+		Version = "0.1.12"
+		out := captureStdout(t, func() { c.printVersionChange("v0.1.10") })
+		if !bytes.Contains([]byte(out), []byte("run migrations")) {
+			t.Errorf("printVersionChange() downgrade output = %q, want it to mention the 0.1.11 annotation, not a canned sentence", out)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package config
+
+// Asset is a single downloadable file attached to a GitHub release.
+type Asset struct {
+	Name string
+	Home string
+	Path string
+	URL  string
+}
+
+// Assets is a collection of Asset.
+type Assets []Asset
@@ -0,0 +1,19 @@
+package config
+
+// Config is afx's top-level configuration.
+type Config struct {
+	SelfUpdate SelfUpdate `yaml:"self_update,omitempty"`
+}
+
+// SelfUpdate holds settings for the `afx self-update` command.
+type SelfUpdate struct {
+	// Filters are regex patterns an asset's name must all match before
+	// self-update will consider it a candidate. They compose with the
+	// command's repeatable --filter flag rather than replacing it.
+	Filters []string `yaml:"filters,omitempty"`
+
+	// PublicKey is the hex-encoded ed25519 public key self-update uses to
+	// verify detached signatures (<asset>.sig / <asset>.minisig) on
+	// release assets before applying them.
+	PublicKey string `yaml:"public_key,omitempty"`
+}
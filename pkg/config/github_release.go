@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// GitHubRelease downloads and unpacks a single asset from a GitHub release
+// for the current OS/arch.
+type GitHubRelease struct {
+	Name   string
+	Client *http.Client
+	Assets Assets
+}
+
+// Download finds the Asset matching the current OS/arch out of r.Assets,
+// downloads it to its Path, and returns it.
+func (r GitHubRelease) Download(ctx context.Context) (Asset, error) {
+	var match *Asset
+	for i, a := range r.Assets {
+		lower := strings.ToLower(a.Name)
+		if strings.Contains(lower, runtime.GOOS) && strings.Contains(lower, runtime.GOARCH) {
+			match = &r.Assets[i]
+			break
+		}
+	}
+	if match == nil {
+		return Asset{}, fmt.Errorf("no asset found for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, match.URL, nil)
+	if err != nil {
+		return Asset{}, err
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Asset{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(match.Home, 0755); err != nil {
+		return Asset{}, err
+	}
+
+	fp, err := os.Create(match.Path)
+	if err != nil {
+		return Asset{}, err
+	}
+	defer fp.Close()
+
+	if _, err := io.Copy(fp, resp.Body); err != nil {
+		return Asset{}, err
+	}
+
+	return *match, nil
+}
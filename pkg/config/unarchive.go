@@ -0,0 +1,116 @@
+package config
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+// Unarchive extracts asset into r.Name inside asset.Home. It walks the
+// archive looking for the entry whose basename equals r.Name rather than
+// assuming a fixed internal path, and understands .tar.gz/.tgz, .tar.xz,
+// .zip (including nested directories), and plain, uncompressed binaries.
+func (r GitHubRelease) Unarchive(asset Asset) error {
+	name := strings.ToLower(asset.Name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return r.untar(asset, func(rd io.Reader) (io.Reader, error) { return gzip.NewReader(rd) })
+	case strings.HasSuffix(name, ".tar.xz"):
+		return r.untar(asset, func(rd io.Reader) (io.Reader, error) { return xz.NewReader(rd) })
+	case strings.HasSuffix(name, ".zip"):
+		return r.unzip(asset)
+	default:
+		return r.copyPlain(asset)
+	}
+}
+
+func (r GitHubRelease) untar(asset Asset, decompress func(io.Reader) (io.Reader, error)) error {
+	fp, err := os.Open(asset.Path)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	dr, err := decompress(fp)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg || filepath.Base(hdr.Name) != r.Name {
+			continue
+		}
+		return writeExecutable(filepath.Join(asset.Home, r.Name), tr)
+	}
+
+	return fmt.Errorf("no entry named %q found in %s", r.Name, asset.Name)
+}
+
+func (r GitHubRelease) unzip(asset Asset) error {
+	zr, err := zip.OpenReader(asset.Path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if filepath.Base(f.Name) != r.Name {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		return writeExecutable(filepath.Join(asset.Home, r.Name), rc)
+	}
+
+	return fmt.Errorf("no entry named %q found in %s", r.Name, asset.Name)
+}
+
+func (r GitHubRelease) copyPlain(asset Asset) error {
+	dest := filepath.Join(asset.Home, r.Name)
+	if dest == asset.Path {
+		return os.Chmod(dest, 0755)
+	}
+
+	src, err := os.Open(asset.Path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return writeExecutable(dest, src)
+}
+
+func writeExecutable(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, r)
+	return err
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+func writeTarGz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0644}); err != nil {
+		t.Fatalf("tar.WriteHeader() failed: %v", err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatalf("tar.Write() failed: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar.Close() failed: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("gzip.Close() failed: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func writeTarXz(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	xw, err := xz.NewWriter(&buf)
+	if err != nil {
+		t.Fatalf("xz.NewWriter() failed: %v", err)
+	}
+	tw := tar.NewWriter(xw)
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0644}); err != nil {
+		t.Fatalf("tar.WriteHeader() failed: %v", err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatalf("tar.Write() failed: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar.Close() failed: %v", err)
+	}
+	if err := xw.Close(); err != nil {
+		t.Fatalf("xz.Close() failed: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func writeZip(t *testing.T, name string, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	fw, err := zw.Create(name)
+	if err != nil {
+		t.Fatalf("zip.Create() failed: %v", err)
+	}
+	if _, err := fw.Write(content); err != nil {
+		t.Fatalf("zip file Write() failed: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip.Close() failed: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestUnarchive(t *testing.T) {
+	content := []byte("the afx binary")
+
+	tests := []struct {
+		format   string
+		filename string
+		data     []byte
+	}{
+		{"tar.gz", "afx.tar.gz", writeTarGz(t, "afx", content)},
+		{"tar.xz", "afx.tar.xz", writeTarXz(t, "afx", content)},
+		{"zip", "afx.zip", writeZip(t, "afx", content)},
+		{"plain", "afx", content},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dir := t.TempDir()
+			archivePath := filepath.Join(dir, tt.filename)
+			if err := os.WriteFile(archivePath, tt.data, 0644); err != nil {
+				t.Fatalf("WriteFile() failed: %v", err)
+			}
+
+			r := GitHubRelease{Name: "afx"}
+			asset := Asset{Name: tt.filename, Home: dir, Path: archivePath}
+
+			if err := r.Unarchive(asset); err != nil {
+				t.Fatalf("Unarchive() failed: %v", err)
+			}
+
+			out := filepath.Join(dir, "afx")
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("ReadFile() on extracted binary failed: %v", err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("extracted content = %q, want %q", got, content)
+			}
+
+			info, err := os.Stat(out)
+			if err != nil {
+				t.Fatalf("Stat() on extracted binary failed: %v", err)
+			}
+			if info.Mode().Perm()&0100 == 0 {
+				t.Errorf("extracted binary mode = %v, want it to be executable", info.Mode())
+			}
+		})
+	}
+}
+
+func TestUnarchiveEntryNotFound(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "afx.tar.gz")
+	if err := os.WriteFile(archivePath, writeTarGz(t, "other", []byte("x")), 0644); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+
+	r := GitHubRelease{Name: "afx"}
+	asset := Asset{Name: "afx.tar.gz", Home: dir, Path: archivePath}
+
+	if err := r.Unarchive(asset); err == nil {
+		t.Error("Unarchive() with no matching entry = nil, want an error")
+	}
+}